@@ -0,0 +1,150 @@
+// Package bizday は営業日（土日祝を除いた日）に関する計算をまとめたパッケージ
+package bizday
+
+import (
+	"fmt"
+	"time"
+)
+
+// HolidaySource は Calendar が祝日判定に使うデータソースを表す
+// (main パッケージの HolidayProvider はこのインタフェースを満たす)
+type HolidaySource interface {
+	IsHoliday(t time.Time) bool
+}
+
+// defaultWeekend は特に指定が無い場合の週末（土曜・日曜）
+var defaultWeekend = map[time.Weekday]bool{
+	time.Saturday: true,
+	time.Sunday:   true,
+}
+
+// Calendar は祝日データと週末の定義を束ねた、営業日計算の基点となるカレンダー
+type Calendar struct {
+	Holidays HolidaySource
+	Weekend  map[time.Weekday]bool
+}
+
+// NewCalendar は holidays と weekendDays から Calendar を生成する
+// weekendDays が空の場合は土曜・日曜を週末として扱う（ME市場向けの金土休みなどはここで指定する）
+func NewCalendar(holidays HolidaySource, weekendDays []time.Weekday) *Calendar {
+	weekend := defaultWeekend
+	if len(weekendDays) > 0 {
+		weekend = make(map[time.Weekday]bool, len(weekendDays))
+		for _, wd := range weekendDays {
+			weekend[wd] = true
+		}
+	}
+	return &Calendar{Holidays: holidays, Weekend: weekend}
+}
+
+// IsWeekend は指定日が週末（Weekend で定義された曜日）かどうかを判定する
+func (c *Calendar) IsWeekend(t time.Time) bool {
+	return c.Weekend[t.Weekday()]
+}
+
+// IsHoliday は指定日が祝日かどうかを判定する
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	if c.Holidays == nil {
+		return false
+	}
+	return c.Holidays.IsHoliday(t)
+}
+
+// IsBusinessDay は週末でも祝日でもない日かどうかを判定する
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	return !c.IsWeekend(t) && !c.IsHoliday(t)
+}
+
+// NextBusinessDay は t より後で最初の営業日を返す
+func (c *Calendar) NextBusinessDay(t time.Time) time.Time {
+	d := t.AddDate(0, 0, 1)
+	for !c.IsBusinessDay(d) {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// PreviousBusinessDay は t より前で最初の営業日を返す
+func (c *Calendar) PreviousBusinessDay(t time.Time) time.Time {
+	d := t.AddDate(0, 0, -1)
+	for !c.IsBusinessDay(d) {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// AddBusinessDays は t から n 営業日後（n が負の場合は n 営業日前）の日付を返す
+// t 自体が営業日かどうかは起点としてのみ扱い、結果には含めない
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	d := t
+	switch {
+	case n > 0:
+		for i := 0; i < n; i++ {
+			d = c.NextBusinessDay(d)
+		}
+	case n < 0:
+		for i := 0; i < -n; i++ {
+			d = c.PreviousBusinessDay(d)
+		}
+	}
+	return d
+}
+
+// BusinessDaysBetween は半開区間 (a, b] に含まれる営業日数を返す
+// a が b より後の場合は負数になる
+func (c *Calendar) BusinessDaysBetween(a, b time.Time) int {
+	if a.After(b) {
+		return -c.BusinessDaysBetween(b, a)
+	}
+	count := 0
+	for d := a.AddDate(0, 0, 1); !d.After(b); d = d.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(d) {
+			count++
+		}
+	}
+	return count
+}
+
+// NthBusinessDayOfMonth は year年month月のうち、n番目の営業日を返す（1始まり）
+func (c *Calendar) NthBusinessDayOfMonth(year int, month time.Month, n int) time.Time {
+	d := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	count := 0
+	for {
+		if c.IsBusinessDay(d) {
+			count++
+			if count == n {
+				return d
+			}
+		}
+		d = d.AddDate(0, 0, 1)
+	}
+}
+
+// LastBusinessDayOfMonth は year年month月の最後の営業日を返す
+func (c *Calendar) LastBusinessDayOfMonth(year int, month time.Month) time.Time {
+	d := time.Date(year, month+1, 1, 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+	for !c.IsBusinessDay(d) {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// weekdayAbbrev は config.toml の weekend = ["Sat", "Sun"] のような英語略称のマッピング
+var weekdayAbbrev = map[string]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// ParseWeekday は "Mon"〜"Sun" の英語略称を time.Weekday に変換する
+func ParseWeekday(s string) (time.Weekday, error) {
+	wd, ok := weekdayAbbrev[s]
+	if !ok {
+		return 0, fmt.Errorf("未知の曜日指定です: %s", s)
+	}
+	return wd, nil
+}
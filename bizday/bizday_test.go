@@ -0,0 +1,182 @@
+package bizday
+
+import (
+	"testing"
+	"time"
+)
+
+// mapHolidaySource はテスト用の固定祝日セット
+type mapHolidaySource map[string]bool
+
+func (m mapHolidaySource) IsHoliday(t time.Time) bool {
+	return m[t.Format("2006-01-02")]
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	holidays := mapHolidaySource{
+		"2026-01-01": true, // 元日(木)
+		"2026-01-12": true, // 成人の日(月)
+	}
+	cal := NewCalendar(holidays, nil)
+
+	cases := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"平日", date(2026, 1, 5), true},
+		{"土曜", date(2026, 1, 3), false},
+		{"日曜", date(2026, 1, 4), false},
+		{"祝日(木)", date(2026, 1, 1), false},
+		{"祝日(月)", date(2026, 1, 12), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := cal.IsBusinessDay(c.date); got != c.want {
+				t.Errorf("IsBusinessDay(%s) = %v, want %v", c.date.Format("2006-01-02"), got, c.want)
+			}
+		})
+	}
+}
+
+func TestCustomWeekend(t *testing.T) {
+	// ME市場向け: 金土が週末
+	cal := NewCalendar(mapHolidaySource{}, []time.Weekday{time.Friday, time.Saturday})
+
+	cases := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"金曜は週末扱い", date(2026, 1, 2), false},
+		{"土曜は週末扱い", date(2026, 1, 3), false},
+		{"日曜は営業日扱い", date(2026, 1, 4), true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := cal.IsBusinessDay(c.date); got != c.want {
+				t.Errorf("IsBusinessDay(%s) = %v, want %v", c.date.Format("2006-01-02"), got, c.want)
+			}
+		})
+	}
+}
+
+func TestConsecutiveHolidays(t *testing.T) {
+	// 2026年のシルバーウィーク相当: 土,日,祝(月),祝(火) が連続するケースを想定
+	holidays := mapHolidaySource{
+		"2026-09-21": true, // 敬老の日(月)
+		"2026-09-22": true, // 国民の休日(火)
+	}
+	cal := NewCalendar(holidays, nil)
+
+	// 金曜(9/18)の次の営業日は、土日と連続する祝日2日を飛び越えた水曜(9/23)のはず
+	got := cal.NextBusinessDay(date(2026, 9, 18))
+	want := date(2026, 9, 23)
+	if !got.Equal(want) {
+		t.Errorf("NextBusinessDay(2026-09-18) = %s, want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+}
+
+func TestSundayHolidaySubstitute(t *testing.T) {
+	// 祝日が日曜日と重なり、振替休日が月曜日に設定されているケース
+	holidays := mapHolidaySource{
+		"2026-11-22": true, // 勤労感謝の日(日)
+		"2026-11-23": true, // 振替休日(月)
+	}
+	cal := NewCalendar(holidays, nil)
+
+	if cal.IsBusinessDay(date(2026, 11, 23)) {
+		t.Errorf("振替休日(2026-11-23)が営業日と判定された")
+	}
+	got := cal.PreviousBusinessDay(date(2026, 11, 24))
+	want := date(2026, 11, 20)
+	if !got.Equal(want) {
+		t.Errorf("PreviousBusinessDay(2026-11-24) = %s, want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	holidays := mapHolidaySource{
+		"2026-01-01": true,
+	}
+	cal := NewCalendar(holidays, nil)
+
+	cases := []struct {
+		name  string
+		start time.Time
+		n     int
+		want  time.Time
+	}{
+		{"3営業日後(祝日を挟む)", date(2025, 12, 30), 3, date(2026, 1, 5)},
+		{"2営業日前", date(2026, 1, 5), -2, date(2025, 12, 31)},
+		{"0日はそのまま", date(2026, 1, 5), 0, date(2026, 1, 5)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := cal.AddBusinessDays(c.start, c.n)
+			if !got.Equal(c.want) {
+				t.Errorf("AddBusinessDays(%s, %d) = %s, want %s",
+					c.start.Format("2006-01-02"), c.n, got.Format("2006-01-02"), c.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	holidays := mapHolidaySource{
+		"2026-01-01": true,
+	}
+	cal := NewCalendar(holidays, nil)
+
+	// 2025-12-29(月)〜2026-01-05(月): 12/30,31,1/2(1/1は祝日),1/5 の4営業日
+	got := cal.BusinessDaysBetween(date(2025, 12, 29), date(2026, 1, 5))
+	want := 4
+	if got != want {
+		t.Errorf("BusinessDaysBetween = %d, want %d", got, want)
+	}
+
+	// 逆順に渡すと符号が反転する
+	if got := cal.BusinessDaysBetween(date(2026, 1, 5), date(2025, 12, 29)); got != -want {
+		t.Errorf("BusinessDaysBetween(逆順) = %d, want %d", got, -want)
+	}
+}
+
+// 月境界: 月末が週末/祝日のケースでも正しく月内最後の営業日が求まることを確認する
+func TestNthAndLastBusinessDayOfMonth(t *testing.T) {
+	// 2026年5月: 5/1(金・祝),5/2(土),5/3(日・祝),5/4(月・祝),5/5(火・祝),5/6(水・振替休日)
+	holidays := mapHolidaySource{
+		"2026-05-01": true,
+		"2026-05-03": true,
+		"2026-05-04": true,
+		"2026-05-05": true,
+		"2026-05-06": true,
+	}
+	cal := NewCalendar(holidays, nil)
+
+	got := cal.NthBusinessDayOfMonth(2026, time.May, 1)
+	want := date(2026, 5, 7) // 木曜
+	if !got.Equal(want) {
+		t.Errorf("NthBusinessDayOfMonth(2026-05, 1) = %s, want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+
+	// 2026年5月末日(31日)は日曜 -> 月内最後の営業日は5/29(金)
+	gotLast := cal.LastBusinessDayOfMonth(2026, time.May)
+	wantLast := date(2026, 5, 29)
+	if !gotLast.Equal(wantLast) {
+		t.Errorf("LastBusinessDayOfMonth(2026-05) = %s, want %s", gotLast.Format("2006-01-02"), wantLast.Format("2006-01-02"))
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	if _, err := ParseWeekday("Xyz"); err == nil {
+		t.Errorf("ParseWeekday(\"Xyz\") が成功してしまった")
+	}
+	wd, err := ParseWeekday("Sat")
+	if err != nil || wd != time.Saturday {
+		t.Errorf("ParseWeekday(\"Sat\") = (%v, %v), want (Saturday, nil)", wd, err)
+	}
+}
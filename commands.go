@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yo-kondo/task_generator/bizday"
+)
+
+// extractStateFlag は引数から --state=PATH を取り除き、statePath に反映した上で
+// 残りの引数を返す。全サブコマンドで共通して受け付ける
+func extractStateFlag(args []string) []string {
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--state=") {
+			statePath = strings.TrimPrefix(arg, "--state=")
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest
+}
+
+// runGenerate は従来の「月を指定してタスク一覧を生成する」処理を行う
+func runGenerate(args []string) {
+	args = extractStateFlag(args)
+
+	targetYMStr, err := parseArgs(args)
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+
+	// 年月のパース (yyyymm -> time.Time)
+	targetDate, err := time.Parse("200601", targetYMStr)
+	if err != nil {
+		log.Fatalf("エラー: 年月の形式が不正です(yyyymm形式で指定してください): %v", err)
+	}
+
+	// 設定ファイルの読み込み
+	cfg, err := loadMainConfig("config.toml")
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+	provider, err := newHolidayProvider(cfg)
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+	if err := provider.Load(targetDate.Year()); err != nil {
+		log.Fatalf("エラー: 祝日データの読み込みに失敗しました: %v", err)
+	}
+	weekendDays, err := parseWeekendDays(cfg.Weekend)
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+	calendar = bizday.NewCalendar(provider, weekendDays)
+	tasks := loadTasks("task.toml")
+
+	state, err := loadState(statePath)
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+
+	// 月の初日・最終日、および繰越判定用に前月の初日・最終日を計算
+	firstDay := targetDate
+	lastDay := firstDay.AddDate(0, 1, -1)
+	prevLastDay := firstDay.AddDate(0, 0, -1)
+	prevFirstDay := time.Date(prevLastDay.Year(), prevLastDay.Month(), 1, 0, 0, 0, 0, firstDay.Location())
+
+	var outputRows []OutputRow
+
+	for _, tmpl := range tasks {
+		if len(tmpl.DependsOn) > 0 && !dependenciesSatisfied(state, tmpl.DependsOn) {
+			log.Printf("情報: 依存タスクが未完了のためスキップします: %s", tmpl.TaskName)
+			continue
+		}
+
+		rule, err := parseRecurrenceRule(tmpl.Repeat)
+		if err != nil {
+			log.Fatalf("エラー: %v (タスク: %s)", err, tmpl.TaskName)
+		}
+
+		for _, date := range rule.Expand(firstDay, lastDay) {
+			row := newOutputRow(tmpl, date, "")
+			outputRows = append(outputRows, row)
+			registerPlannedInstance(state, row)
+		}
+
+		if tmpl.CarryOver {
+			if row := carryOverRow(state, tmpl, prevFirstDay, prevLastDay, firstDay); row != nil {
+				outputRows = append(outputRows, *row)
+				registerPlannedInstance(state, *row)
+			}
+		}
+	}
+
+	// 日付順にソート
+	sort.Slice(outputRows, func(i, j int) bool {
+		// 日付が同じなら時刻で比較、それも同じならタスク名で比較（安定ソートのため）
+		if outputRows[i].Date.Equal(outputRows[j].Date) {
+			if outputRows[i].Time == outputRows[j].Time {
+				return outputRows[i].TaskName < outputRows[j].TaskName
+			}
+			return outputRows[i].Time < outputRows[j].Time
+		}
+		return outputRows[i].Date.Before(outputRows[j].Date)
+	})
+
+	formatter, err := newFormatter(outputFormat)
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+	output, err := formatter.Format(outputRows)
+	if err != nil {
+		log.Fatalf("エラー: 出力の整形に失敗しました: %v", err)
+	}
+
+	if outputPath == "" {
+		fmt.Print(output)
+	} else if err := os.WriteFile(outputPath, []byte(output), 0644); err != nil {
+		log.Fatalf("エラー: 出力ファイル(%s)の書き込みに失敗しました: %v", outputPath, err)
+	}
+
+	if err := saveState(statePath, state); err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+}
+
+// parseWeekendDays は config.toml の weekend = ["Sat", "Sun"] のような設定を
+// []time.Weekday に変換する。空の場合は nil を返し、Calendar 側のデフォルト(土日)に任せる
+func parseWeekendDays(names []string) ([]time.Weekday, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	days := make([]time.Weekday, 0, len(names))
+	for _, name := range names {
+		wd, err := bizday.ParseWeekday(name)
+		if err != nil {
+			return nil, fmt.Errorf("[weekend] の指定が不正です: %w", err)
+		}
+		days = append(days, wd)
+	}
+	return days, nil
+}
+
+// newOutputRow は TaskTemplate と日付から出力行を組み立てる
+// prefix は繰越タスクの「[繰越]」表示などに使う
+func newOutputRow(tmpl TaskTemplate, date time.Time, prefix string) OutputRow {
+	return OutputRow{
+		Date:          date,
+		Time:          tmpl.Time,
+		EstimatedTime: tmpl.EstimatedTime,
+		ActualTime:    "",
+		Project:       tmpl.Project,
+		TaskName:      prefix + tmpl.TaskName,
+		BaseTaskName:  tmpl.TaskName,
+		TaskNote:      tmpl.TaskNote,
+	}
+}
+
+// carryOverRow は前月分の未完了インスタンスがあれば、それを carried 状態にし、
+// 今月最初の営業日に「[繰越]」を付けて再生成する出力行を返す（無ければ nil）
+func carryOverRow(state *State, tmpl TaskTemplate, prevFirstDay, prevLastDay, firstDay time.Time) *OutputRow {
+	pending := pendingInstanceInRange(state, tmpl.Project, tmpl.TaskName,
+		prevFirstDay.Format("2006-01-02"), prevLastDay.Format("2006-01-02"))
+	if pending == nil {
+		return nil
+	}
+	pending.Status = StatusCarried
+
+	d := monthFirstBusinessDay(firstDay.Year(), firstDay.Month())
+	row := newOutputRow(tmpl, d, "[繰越]")
+	return &row
+}
+
+// runMarkDone は指定インスタンスIDのステータスを done にする
+func runMarkDone(args []string) {
+	args = extractStateFlag(args)
+	if len(args) < 1 {
+		log.Fatal("エラー: mark-done にはインスタンスIDを指定してください。\n例: go run main.go mark-done 2026-01-30:経理:月次レポート")
+	}
+	id := args[0]
+
+	state, err := loadState(statePath)
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+	inst, ok := state.Instances[id]
+	if !ok {
+		log.Fatalf("エラー: 指定されたIDのインスタンスが見つかりません: %s", id)
+	}
+	inst.Status = StatusDone
+	inst.LastCompleted = time.Now().Format("2006-01-02")
+
+	if err := saveState(statePath, state); err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+	fmt.Printf("完了にしました: %s\n", id)
+}
+
+// runListOpen は未完了（done/skipped 以外）のインスタンス一覧を表示する
+func runListOpen(args []string) {
+	args = extractStateFlag(args)
+	if len(args) > 0 {
+		log.Fatalf("エラー: list-open に未対応の引数が指定されました: %s", strings.Join(args, " "))
+	}
+
+	state, err := loadState(statePath)
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+	for _, inst := range sortedInstances(state) {
+		if inst.Status == StatusDone || inst.Status == StatusSkipped {
+			continue
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", inst.ID, inst.Date, inst.Project, inst.TaskName, inst.Status)
+	}
+}
+
+// runStatus は指定年月に属するインスタンスの一覧を表示する
+func runStatus(args []string) {
+	args = extractStateFlag(args)
+	if len(args) < 1 {
+		log.Fatal("エラー: status には対象年月(yyyymm)を指定してください。\n例: go run main.go status 202511")
+	}
+	targetDate, err := time.Parse("200601", args[0])
+	if err != nil {
+		log.Fatalf("エラー: 年月の形式が不正です(yyyymm形式で指定してください): %v", err)
+	}
+	firstDay := targetDate
+	lastDay := firstDay.AddDate(0, 1, -1)
+
+	state, err := loadState(statePath)
+	if err != nil {
+		log.Fatalf("エラー: %v", err)
+	}
+	for _, inst := range sortedInstances(state) {
+		if inst.Date < firstDay.Format("2006-01-02") || inst.Date > lastDay.Format("2006-01-02") {
+			continue
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", inst.ID, inst.Date, inst.Project, inst.TaskName, inst.Status)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// Formatter: 出力形式を抽象化するインタフェース
+// ---------------------------------------------------------
+
+// Formatter は生成済みの OutputRow 一覧を出力用テキストに変換する
+type Formatter interface {
+	Format(rows []OutputRow) (string, error)
+}
+
+// newFormatter は --format の値に対応する Formatter を返す
+func newFormatter(format string) (Formatter, error) {
+	switch format {
+	case "", "tsv":
+		return TSVFormatter{}, nil
+	case "csv":
+		return CSVFormatter{}, nil
+	case "json":
+		return JSONFormatter{}, nil
+	case "ics":
+		return ICSFormatter{}, nil
+	case "md":
+		return MarkdownFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("未対応の --format 値です: %s (tsv/csv/json/ics/md のいずれかを指定してください)", format)
+	}
+}
+
+// ---------------------------------------------------------
+// TSV / CSV
+// ---------------------------------------------------------
+
+// TSVFormatter は従来どおりのタブ区切り出力
+type TSVFormatter struct{}
+
+func (f TSVFormatter) Format(rows []OutputRow) (string, error) {
+	var buf bytes.Buffer
+	for _, row := range rows {
+		fmt.Fprintf(&buf, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.Date.Format("2006-01-02"),
+			row.Time,
+			row.EstimatedTime,
+			row.ActualTime,
+			row.Project,
+			row.TaskName,
+			row.TaskNote,
+		)
+	}
+	return buf.String(), nil
+}
+
+// CSVFormatter はカンマ区切り出力（値中のカンマ・改行はダブルクォートでエスケープされる）
+type CSVFormatter struct{}
+
+func (f CSVFormatter) Format(rows []OutputRow) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for _, row := range rows {
+		record := []string{
+			row.Date.Format("2006-01-02"),
+			row.Time,
+			row.EstimatedTime,
+			row.ActualTime,
+			row.Project,
+			row.TaskName,
+			row.TaskNote,
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ---------------------------------------------------------
+// JSON
+// ---------------------------------------------------------
+
+// JSONFormatter は OutputRow のスライスをそのままJSON配列として出力する
+type JSONFormatter struct{}
+
+// jsonRow は JSON 出力時のフィールド名を定義する
+type jsonRow struct {
+	Date          string `json:"date"`
+	Time          string `json:"time"`
+	EstimatedTime string `json:"estimated_time"`
+	ActualTime    string `json:"actual_time"`
+	Project       string `json:"project"`
+	TaskName      string `json:"task_name"`
+	TaskNote      string `json:"task_note"`
+}
+
+func (f JSONFormatter) Format(rows []OutputRow) (string, error) {
+	jsonRows := make([]jsonRow, 0, len(rows))
+	for _, row := range rows {
+		jsonRows = append(jsonRows, jsonRow{
+			Date:          row.Date.Format("2006-01-02"),
+			Time:          row.Time,
+			EstimatedTime: row.EstimatedTime,
+			ActualTime:    row.ActualTime,
+			Project:       row.Project,
+			TaskName:      row.TaskName,
+			TaskNote:      row.TaskNote,
+		})
+	}
+	data, err := json.MarshalIndent(jsonRows, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// ---------------------------------------------------------
+// Markdown
+// ---------------------------------------------------------
+
+// MarkdownFormatter はMarkdownテーブル形式で出力する
+type MarkdownFormatter struct{}
+
+func (f MarkdownFormatter) Format(rows []OutputRow) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("| 日付 | 時刻 | 見積時間 | 実績時間 | プロジェクト | タスク名 | メモ |\n")
+	buf.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, row := range rows {
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			row.Date.Format("2006-01-02"),
+			row.Time,
+			row.EstimatedTime,
+			row.ActualTime,
+			row.Project,
+			escapeMarkdownCell(row.TaskName),
+			escapeMarkdownCell(row.TaskNote),
+		)
+	}
+	return buf.String(), nil
+}
+
+// escapeMarkdownCell はテーブルを壊す可能性のある文字をエスケープする
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return s
+}
+
+// ---------------------------------------------------------
+// iCalendar (.ics)
+// ---------------------------------------------------------
+
+// ICSFormatter はiCalendar(.ics)形式で出力する
+// UIDは (日付, タスク名, プロジェクト) のハッシュから決定的に生成するため、
+// 同じ月を再生成してもUIDが変わらず、取り込み先のカレンダーでイベントが重複しない
+type ICSFormatter struct{}
+
+func (f ICSFormatter) Format(rows []OutputRow) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("BEGIN:VCALENDAR\r\n")
+	buf.WriteString("VERSION:2.0\r\n")
+	buf.WriteString("PRODID:-//task_generator//JP\r\n")
+
+	for _, row := range rows {
+		start, end, err := eventTimeRange(row)
+		if err != nil {
+			log.Printf("警告: タスク(%s)の日時の計算に失敗したためICS出力から除外します: %v", row.TaskName, err)
+			continue
+		}
+
+		buf.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&buf, "UID:%s\r\n", eventUID(row))
+		fmt.Fprintf(&buf, "DTSTAMP:%sT000000Z\r\n", row.Date.Format("20060102"))
+		fmt.Fprintf(&buf, "DTSTART:%s\r\n", start.Format("20060102T150405"))
+		fmt.Fprintf(&buf, "DTEND:%s\r\n", end.Format("20060102T150405"))
+		fmt.Fprintf(&buf, "SUMMARY:%s\r\n", icsEscape(fmt.Sprintf("%s: %s", row.Project, row.TaskName)))
+		fmt.Fprintf(&buf, "DESCRIPTION:%s\r\n", icsEscape(row.TaskNote))
+		fmt.Fprintf(&buf, "CATEGORIES:%s\r\n", icsEscape(row.Project))
+		buf.WriteString("END:VEVENT\r\n")
+	}
+
+	buf.WriteString("END:VCALENDAR\r\n")
+	return buf.String(), nil
+}
+
+// eventTimeRange は row.Time と row.EstimatedTime から予定の開始・終了時刻を求める
+func eventTimeRange(row OutputRow) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02 15:04", row.Date.Format("2006-01-02")+" "+row.Time, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("時刻(%s)の解析に失敗しました: %w", row.Time, err)
+	}
+
+	duration, err := time.ParseDuration(row.EstimatedTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("見積時間(%s)の解析に失敗しました: %w", row.EstimatedTime, err)
+	}
+
+	return start, start.Add(duration), nil
+}
+
+// eventUID は (日付, タスク名, プロジェクト) から決定的なUIDを生成する
+func eventUID(row OutputRow) string {
+	key := fmt.Sprintf("%s|%s|%s", row.Date.Format("2006-01-02"), row.TaskName, row.Project)
+	sum := sha1.Sum([]byte(key))
+	return fmt.Sprintf("%x@task_generator", sum)
+}
+
+// icsEscape はiCalendarのテキストプロパティで特殊扱いされる文字をエスケープする
+func icsEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	s = strings.ReplaceAll(s, ",", "\\,")
+	s = strings.ReplaceAll(s, ";", "\\;")
+	return s
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ---------------------------------------------------------
+// HolidayProvider: 祝日データソースを抽象化するインタフェース
+// ---------------------------------------------------------
+
+// HolidayProvider は祝日判定に使うデータソースを抽象化する
+type HolidayProvider interface {
+	// Load は対象年の祝日データを読み込む（取得・キャッシュが必要な実装はここで行う）
+	Load(year int) error
+	// IsHoliday は指定日が祝日かどうかを判定する
+	IsHoliday(t time.Time) bool
+	// Name は指定日の祝日名を返す（祝日でなければ空文字）
+	Name(t time.Time) string
+}
+
+// HolidayConfig は holiday.toml (source=toml のとき) の構造を定義
+type HolidayConfig struct {
+	Holidays []Holiday `toml:"holiday"`
+}
+
+type Holiday struct {
+	Name        string `toml:"name"`
+	Date        string `toml:"holiday"`
+	HolidayNote string `toml:"holiday_note"`
+}
+
+// HolidaySourceConfig は config.toml の [holiday] ブロックに対応する
+type HolidaySourceConfig struct {
+	Source    string `toml:"source"`     // "toml"(デフォルト) | "japan" | "ics"
+	File      string `toml:"file"`       // source=toml のときに読み込む祝日ファイル
+	ICSURL    string `toml:"ics_url"`    // source=ics のときの iCalendar 取得元URL
+	CachePath string `toml:"cache_path"` // source=ics のときのキャッシュファイルパス
+}
+
+// mainConfig は config.toml の構造を定義
+type mainConfig struct {
+	Holiday HolidaySourceConfig `toml:"holiday"`
+	Weekend []string            `toml:"weekend"` // 週末とみなす曜日(例: ["Fri","Sat"])。未指定なら土日
+}
+
+// loadMainConfig は configPath を読み込む。ファイルが存在しない場合はゼロ値を返す（後方互換）
+func loadMainConfig(configPath string) (mainConfig, error) {
+	var cfg mainConfig
+	if _, err := os.Stat(configPath); err == nil {
+		if _, err := toml.DecodeFile(configPath, &cfg); err != nil {
+			return cfg, fmt.Errorf("設定ファイル(%s)の読み込みに失敗しました: %w", configPath, err)
+		}
+	}
+	return cfg, nil
+}
+
+// newHolidayProvider は cfg.Holiday の設定に対応する HolidayProvider を生成するファクトリ
+func newHolidayProvider(cfg mainConfig) (HolidayProvider, error) {
+	switch cfg.Holiday.Source {
+	case "", "toml":
+		file := cfg.Holiday.File
+		if file == "" {
+			file = "holiday.toml"
+		}
+		return &TomlHolidayProvider{FilePath: file}, nil
+
+	case "japan":
+		return &JapanHolidayProvider{}, nil
+
+	case "ics":
+		if cfg.Holiday.ICSURL == "" {
+			return nil, fmt.Errorf("[holiday] source=ics には ics_url の指定が必須です")
+		}
+		cachePath := cfg.Holiday.CachePath
+		if cachePath == "" {
+			cachePath = "holiday_cache.json"
+		}
+		return &ICSHolidayProvider{URL: cfg.Holiday.ICSURL, CachePath: cachePath}, nil
+
+	default:
+		return nil, fmt.Errorf("未対応の [holiday] source です: %s", cfg.Holiday.Source)
+	}
+}
+
+// ---------------------------------------------------------
+// TomlHolidayProvider: 従来の holiday.toml をそのまま読み込む実装
+// ---------------------------------------------------------
+
+// TomlHolidayProvider は holiday.toml 形式のファイルから祝日一覧を読み込む
+type TomlHolidayProvider struct {
+	FilePath string
+
+	holidays map[string]string // yyyy-mm-dd -> 祝日名
+}
+
+// Load はファイル全体を読み込む。holiday.toml は年を区別せず全期間分を保持しているため、
+// year は使用しない
+func (p *TomlHolidayProvider) Load(year int) error {
+	var config HolidayConfig
+	if _, err := toml.DecodeFile(p.FilePath, &config); err != nil {
+		return fmt.Errorf("祝日ファイル(%s)の読み込みに失敗しました: %w", p.FilePath, err)
+	}
+
+	p.holidays = make(map[string]string)
+	for _, h := range config.Holidays {
+		p.holidays[h.Date] = h.Name
+	}
+	return nil
+}
+
+func (p *TomlHolidayProvider) IsHoliday(t time.Time) bool {
+	_, ok := p.holidays[t.Format("2006-01-02")]
+	return ok
+}
+
+func (p *TomlHolidayProvider) Name(t time.Time) string {
+	return p.holidays[t.Format("2006-01-02")]
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICSHolidayProvider は iCalendar (ics) / Google Calendar の公開URLから祝日を取得する
+// 取得結果は年ごとに CachePath のJSONファイルへキャッシュし、以降はキャッシュを優先利用する
+type ICSHolidayProvider struct {
+	URL       string
+	CachePath string
+
+	holidays map[string]string
+}
+
+type icsCache struct {
+	Years map[string][]icsCacheEntry `json:"years"`
+}
+
+type icsCacheEntry struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+func (p *ICSHolidayProvider) Load(year int) error {
+	cache, err := p.loadCache()
+	if err != nil {
+		return err
+	}
+
+	yearKey := strconv.Itoa(year)
+	entries, ok := cache.Years[yearKey]
+	if !ok {
+		events, err := fetchICSEvents(p.URL)
+		if err != nil {
+			return fmt.Errorf("iCalendarの取得に失敗しました(%s): %w", p.URL, err)
+		}
+		for _, ev := range events {
+			if ev.Start.Year() != year {
+				continue
+			}
+			entries = append(entries, icsCacheEntry{Date: ev.Start.Format("2006-01-02"), Name: ev.Summary})
+		}
+		cache.Years[yearKey] = entries
+		if err := p.saveCache(cache); err != nil {
+			return err
+		}
+	}
+
+	p.holidays = make(map[string]string)
+	for _, e := range entries {
+		p.holidays[e.Date] = e.Name
+	}
+	return nil
+}
+
+func (p *ICSHolidayProvider) IsHoliday(t time.Time) bool {
+	_, ok := p.holidays[t.Format("2006-01-02")]
+	return ok
+}
+
+func (p *ICSHolidayProvider) Name(t time.Time) string {
+	return p.holidays[t.Format("2006-01-02")]
+}
+
+func (p *ICSHolidayProvider) loadCache() (icsCache, error) {
+	cache := icsCache{Years: make(map[string][]icsCacheEntry)}
+
+	data, err := os.ReadFile(p.CachePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+		return cache, fmt.Errorf("祝日キャッシュ(%s)の読み込みに失敗しました: %w", p.CachePath, err)
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, fmt.Errorf("祝日キャッシュ(%s)の解析に失敗しました: %w", p.CachePath, err)
+	}
+	if cache.Years == nil {
+		cache.Years = make(map[string][]icsCacheEntry)
+	}
+	return cache, nil
+}
+
+func (p *ICSHolidayProvider) saveCache(cache icsCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("祝日キャッシュのエンコードに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(p.CachePath, data, 0644); err != nil {
+		return fmt.Errorf("祝日キャッシュ(%s)の書き込みに失敗しました: %w", p.CachePath, err)
+	}
+	return nil
+}
+
+// icsEvent は ics ファイルから読み取った VEVENT の最低限の情報
+type icsEvent struct {
+	Start   time.Time
+	Summary string
+}
+
+// fetchICSEvents は指定URLから iCalendar データを取得し、VEVENTごとの開始日とタイトルを返す
+// RRULE等の繰り返しイベントには対応せず、単発の DTSTART/SUMMARY のみを読み取る簡易パーサー
+func fetchICSEvents(url string) ([]icsEvent, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("予期しないステータスコードです: %d", resp.StatusCode)
+	}
+
+	var events []icsEvent
+	var cur icsEvent
+	inEvent := false
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case line == "BEGIN:VEVENT":
+			inEvent = true
+			cur = icsEvent{}
+		case line == "END:VEVENT":
+			if inEvent && !cur.Start.IsZero() {
+				events = append(events, cur)
+			}
+			inEvent = false
+		case inEvent && strings.HasPrefix(line, "SUMMARY:"):
+			cur.Summary = strings.TrimPrefix(line, "SUMMARY:")
+		case inEvent && strings.HasPrefix(line, "DTSTART"):
+			cur.Start = parseICSDate(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// parseICSDate は "DTSTART;VALUE=DATE:20260101" や "DTSTART:20260101T000000Z" 形式から日付を取り出す
+func parseICSDate(line string) time.Time {
+	idx := strings.LastIndex(line, ":")
+	if idx < 0 {
+		return time.Time{}
+	}
+	value := line[idx+1:]
+	if len(value) < 8 {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation("20060102", value[:8], time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
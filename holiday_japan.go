@@ -0,0 +1,142 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// JapanHolidayProvider は日本の国民の祝日をコードで計算して提供する
+// (春分の日・秋分の日は天文計算の近似式で求めており、1980〜2099年の範囲で有効)
+type JapanHolidayProvider struct {
+	year     int
+	holidays map[string]string
+}
+
+func (p *JapanHolidayProvider) Load(year int) error {
+	p.year = year
+	p.holidays = computeJapanHolidays(year)
+	return nil
+}
+
+// IsHoliday は Load された年をまたぐ日付を渡された場合、その年を都度計算し直す
+// (月初/月末の営業日調整で前後の月にはみ出す場合に発生しうるため)
+func (p *JapanHolidayProvider) IsHoliday(t time.Time) bool {
+	return p.holidayName(t) != ""
+}
+
+func (p *JapanHolidayProvider) Name(t time.Time) string {
+	return p.holidayName(t)
+}
+
+func (p *JapanHolidayProvider) holidayName(t time.Time) string {
+	holidays := p.holidays
+	if t.Year() != p.year {
+		holidays = computeJapanHolidays(t.Year())
+	}
+	return holidays[t.Format("2006-01-02")]
+}
+
+// computeJapanHolidays は指定年の祝日一覧（振替休日・国民の休日を含む）を計算する
+func computeJapanHolidays(year int) map[string]string {
+	h := make(map[string]string)
+	set := func(t time.Time, name string) {
+		h[t.Format("2006-01-02")] = name
+	}
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	set(date(1, 1), "元日")
+	set(nthWeekdayOfMonth(year, 1, time.Monday, 2), "成人の日")
+	set(date(2, 11), "建国記念の日")
+	set(date(2, 23), "天皇誕生日")
+	set(vernalEquinoxDay(year), "春分の日")
+	set(date(4, 29), "昭和の日")
+	set(date(5, 3), "憲法記念日")
+	set(date(5, 4), "みどりの日")
+	set(date(5, 5), "こどもの日")
+	set(nthWeekdayOfMonth(year, 7, time.Monday, 3), "海の日")
+	set(date(8, 11), "山の日")
+	set(nthWeekdayOfMonth(year, 9, time.Monday, 3), "敬老の日")
+	set(autumnalEquinoxDay(year), "秋分の日")
+	set(nthWeekdayOfMonth(year, 10, time.Monday, 2), "スポーツの日")
+	set(date(11, 3), "文化の日")
+	set(date(11, 23), "勤労感謝の日")
+
+	applySubstituteHolidays(h)
+	applyNationalHoliday(h, year)
+	return h
+}
+
+// nthWeekdayOfMonth は year年month月のうち、n番目の weekday を返す（1始まり）
+func nthWeekdayOfMonth(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	d := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	for d.Weekday() != weekday {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d.AddDate(0, 0, 7*(n-1))
+}
+
+// vernalEquinoxDay は春分の日を天文計算の近似式で求める
+func vernalEquinoxDay(year int) time.Time {
+	day := int(math.Floor(20.8431+0.242194*float64(year-1980))) - int(math.Floor(float64(year-1980)/4))
+	return time.Date(year, 3, day, 0, 0, 0, 0, time.Local)
+}
+
+// autumnalEquinoxDay は秋分の日を天文計算の近似式で求める
+func autumnalEquinoxDay(year int) time.Time {
+	day := int(math.Floor(23.2488+0.242194*float64(year-1980))) - int(math.Floor(float64(year-1980)/4))
+	return time.Date(year, 9, day, 0, 0, 0, 0, time.Local)
+}
+
+// applySubstituteHolidays は祝日が日曜日と重なる場合、直後の平日に振替休日を追加する
+func applySubstituteHolidays(h map[string]string) {
+	type entry struct {
+		date time.Time
+		name string
+	}
+	entries := make([]entry, 0, len(h))
+	for dateStr, name := range h {
+		d, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{date: d, name: name})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].date.Before(entries[j].date) })
+
+	for _, e := range entries {
+		if e.date.Weekday() != time.Sunday {
+			continue
+		}
+		d := e.date.AddDate(0, 0, 1)
+		for {
+			if _, exists := h[d.Format("2006-01-02")]; !exists {
+				h[d.Format("2006-01-02")] = "振替休日"
+				break
+			}
+			d = d.AddDate(0, 0, 1)
+		}
+	}
+}
+
+// applyNationalHoliday は前後を祝日に挟まれた平日を「国民の休日」として追加する
+func applyNationalHoliday(h map[string]string, year int) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(year, 12, 31, 0, 0, 0, 0, time.Local)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dateStr := d.Format("2006-01-02")
+		if _, ok := h[dateStr]; ok {
+			continue
+		}
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		_, prevOk := h[d.AddDate(0, 0, -1).Format("2006-01-02")]
+		_, nextOk := h[d.AddDate(0, 0, 1).Format("2006-01-02")]
+		if prevOk && nextOk {
+			h[dateStr] = "国民の休日"
+		}
+	}
+}
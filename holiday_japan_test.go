@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNthWeekdayOfMonth(t *testing.T) {
+	// 2026年1月の第2月曜日は1/12
+	got := nthWeekdayOfMonth(2026, 1, time.Monday, 2)
+	want := d(2026, 1, 12)
+	if !got.Equal(want) {
+		t.Errorf("nthWeekdayOfMonth(2026,1,月,2) = %s, want %s", got.Format("2006-01-02"), want.Format("2006-01-02"))
+	}
+}
+
+func TestEquinoxDays(t *testing.T) {
+	cases := []struct {
+		year         int
+		wantVernal   time.Time
+		wantAutumnal time.Time
+	}{
+		{1990, d(1990, 3, 21), d(1990, 9, 23)},
+		{2000, d(2000, 3, 20), d(2000, 9, 23)},
+		{2012, d(2012, 3, 20), d(2012, 9, 22)},
+		{2026, d(2026, 3, 20), d(2026, 9, 23)},
+	}
+	for _, c := range cases {
+		t.Run(c.wantVernal.Format("2006"), func(t *testing.T) {
+			if got := vernalEquinoxDay(c.year); !got.Equal(c.wantVernal) {
+				t.Errorf("vernalEquinoxDay(%d) = %s, want %s", c.year, got.Format("2006-01-02"), c.wantVernal.Format("2006-01-02"))
+			}
+			if got := autumnalEquinoxDay(c.year); !got.Equal(c.wantAutumnal) {
+				t.Errorf("autumnalEquinoxDay(%d) = %s, want %s", c.year, got.Format("2006-01-02"), c.wantAutumnal.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+// TestGoldenWeek2026SubstituteChain は、祝日(5/3 憲法記念日)が日曜日と重なり、
+// 翌日以降(5/4 みどりの日, 5/5 こどもの日)も既に祝日で埋まっている場合、
+// 振替休日が最初の非祝日である5/6(水)まで連鎖することを確認する
+func TestGoldenWeek2026SubstituteChain(t *testing.T) {
+	h := computeJapanHolidays(2026)
+
+	cases := []struct {
+		date string
+		want string
+	}{
+		{"2026-05-03", "憲法記念日"},
+		{"2026-05-04", "みどりの日"},
+		{"2026-05-05", "こどもの日"},
+		{"2026-05-06", "振替休日"},
+	}
+	for _, c := range cases {
+		if got := h[c.date]; got != c.want {
+			t.Errorf("h[%s] = %q, want %q", c.date, got, c.want)
+		}
+	}
+}
+
+// TestSeptember2026NationalHoliday は、敬老の日(9/21 月)と秋分の日(9/23 水)に
+// 挟まれた平日(9/22 火)が「国民の休日」として扱われることを確認する
+func TestSeptember2026NationalHoliday(t *testing.T) {
+	h := computeJapanHolidays(2026)
+
+	cases := []struct {
+		date string
+		want string
+	}{
+		{"2026-09-21", "敬老の日"},
+		{"2026-09-22", "国民の休日"},
+		{"2026-09-23", "秋分の日"},
+	}
+	for _, c := range cases {
+		if got := h[c.date]; got != c.want {
+			t.Errorf("h[%s] = %q, want %q", c.date, got, c.want)
+		}
+	}
+}
+
+func TestJapanHolidayProviderIsHolidayAcrossYearBoundary(t *testing.T) {
+	p := &JapanHolidayProvider{}
+	if err := p.Load(2026); err != nil {
+		t.Fatalf("Load(2026) error = %v", err)
+	}
+	if !p.IsHoliday(d(2026, 1, 1)) {
+		t.Error("2026-01-01(元日) が祝日と判定されなかった")
+	}
+	// Load した年をまたいだ日付(2027年)でも正しく判定できる
+	if !p.IsHoliday(d(2027, 1, 1)) {
+		t.Error("Load(2026) 後に 2027-01-01(元日) を渡しても祝日と判定されなかった")
+	}
+	if p.IsHoliday(d(2026, 1, 2)) {
+		t.Error("2026-01-02(祝日ではない) が祝日と判定された")
+	}
+}
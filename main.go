@@ -1,262 +1,212 @@
-/*
-# タスク一覧の繰り返しタスク作成
-*/
-
-package main
-
-import (
-	"fmt"
-	"log"
-	"os"
-	"sort"
-	"strings"
-	"time"
-
-	"github.com/BurntSushi/toml"
-)
-
-// ---------------------------------------------------------
-// データ構造定義
-// ---------------------------------------------------------
-
-// HolidayConfig は holiday.toml の構造を定義
-type HolidayConfig struct {
-	Holidays []Holiday `toml:"holiday"`
-}
-
-type Holiday struct {
-	Name        string `toml:"name"`
-	Date        string `toml:"holiday"`
-	HolidayNote string `toml:"holiday_note"`
-}
-
-// TaskConfig は task.toml の構造を定義
-type TaskConfig struct {
-	Tasks []TaskTemplate `toml:"task"`
-}
-
-type TaskTemplate struct {
-	Repeat        string `toml:"repeat"`
-	Time          string `toml:"time"`
-	EstimatedTime string `toml:"estimated_time"`
-	Project       string `toml:"project"`
-	TaskName      string `toml:"task_name"`
-	TaskNote      string `toml:"task_note"`
-}
-
-// OutputRow は出力する1行分のデータを保持
-type OutputRow struct {
-	Date          time.Time
-	Time          string
-	EstimatedTime string
-	ActualTime    string // 常に空白
-	Project       string
-	TaskName      string
-	TaskNote      string
-}
-
-// ---------------------------------------------------------
-// グローバル定数・変数
-// ---------------------------------------------------------
-
-// 曜日文字列と time.Weekday のマッピング
-var weekdayMap = map[string]time.Weekday{
-	"日曜日": time.Sunday,
-	"月曜日": time.Monday,
-	"火曜日": time.Tuesday,
-	"水曜日": time.Wednesday,
-	"木曜日": time.Thursday,
-	"金曜日": time.Friday,
-	"土曜日": time.Saturday,
-}
-
-// 祝日判定用マップ (key: yyyy-mm-dd)
-var holidayMap map[string]bool
-
-// ---------------------------------------------------------
-// メイン処理
-// ---------------------------------------------------------
-
-func main() {
-	// 1. コマンドライン引数のチェック
-	if len(os.Args) < 2 {
-		log.Fatal("エラー: 引数に年月(yyyymm)を指定してください。\n例: go run main.go 202511")
-	}
-	targetYMStr := os.Args[1]
-
-	// 年月のパース (yyyymm -> time.Time)
-	targetDate, err := time.Parse("200601", targetYMStr)
-	if err != nil {
-		log.Fatalf("エラー: 年月の形式が不正です(yyyymm形式で指定してください): %v", err)
-	}
-
-	// 2. 設定ファイルの読み込み
-	loadHolidays("holiday.toml")
-	tasks := loadTasks("task.toml")
-
-	// 3. タスクの生成処理
-	var outputRows []OutputRow
-
-	// 月の初日と最終日を計算
-	firstDay := targetDate
-	lastDay := firstDay.AddDate(0, 1, -1)
-
-	for _, tmpl := range tasks {
-		generatedDates := []time.Time{}
-
-		switch {
-		case tmpl.Repeat == "毎日":
-			// 毎日：土日祝を除く平日
-			for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
-				if isBusinessDay(d) {
-					generatedDates = append(generatedDates, d)
-				}
-			}
-
-		case tmpl.Repeat == "月初":
-			// 月初：月の初めの平日
-			d := firstDay
-			// 平日が見つかるまで進める
-			for !d.After(lastDay) {
-				if isBusinessDay(d) {
-					generatedDates = append(generatedDates, d)
-					break
-				}
-				d = d.AddDate(0, 0, 1)
-			}
-
-		case tmpl.Repeat == "月末":
-			// 月末：月の最後の平日
-			d := lastDay
-			// 平日が見つかるまで戻る
-			for !d.Before(firstDay) {
-				if isBusinessDay(d) {
-					generatedDates = append(generatedDates, d)
-					break
-				}
-				d = d.AddDate(0, 0, -1)
-			}
-
-		case strings.HasPrefix(tmpl.Repeat, "毎週"):
-			// 毎週◯曜日
-			targetWeekdayStr := strings.TrimPrefix(tmpl.Repeat, "毎週")
-			targetWeekday, ok := weekdayMap[targetWeekdayStr]
-			if !ok {
-				log.Printf("警告: 未知の曜日指定です: %s (タスク: %s)", tmpl.Repeat, tmpl.TaskName)
-				continue
-			}
-
-			// 月内の指定曜日を全て探す
-			for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
-				if d.Weekday() == targetWeekday {
-					// 祝日チェック
-					finalDate := d
-					if isHoliday(d) {
-						// 祝日の場合、1日前の平日を探す
-						// ※前日が前月になる場合でも、その日付でタスクを作成するのが一般的と判断
-						finalDate = getPreviousBusinessDay(d)
-					}
-					generatedDates = append(generatedDates, finalDate)
-				}
-			}
-
-		default:
-			log.Fatalf("エラー: 未対応の繰り返し設定です: %s (タスク: %s)", tmpl.Repeat, tmpl.TaskName)
-		}
-
-		// 生成された日付ごとに出力行を作成
-		for _, date := range generatedDates {
-			row := OutputRow{
-				Date:          date,
-				Time:          tmpl.Time,
-				EstimatedTime: tmpl.EstimatedTime,
-				ActualTime:    "",
-				Project:       tmpl.Project,
-				TaskName:      tmpl.TaskName,
-				TaskNote:      tmpl.TaskNote,
-			}
-			outputRows = append(outputRows, row)
-		}
-	}
-
-	// 4. 日付順にソート
-	sort.Slice(outputRows, func(i, j int) bool {
-		// 日付が同じなら時刻で比較、それも同じならタスク名で比較（安定ソートのため）
-		if outputRows[i].Date.Equal(outputRows[j].Date) {
-			if outputRows[i].Time == outputRows[j].Time {
-				return outputRows[i].TaskName < outputRows[j].TaskName
-			}
-			return outputRows[i].Time < outputRows[j].Time
-		}
-		return outputRows[i].Date.Before(outputRows[j].Date)
-	})
-
-	// 5. 出力
-	for _, row := range outputRows {
-		fmt.Printf("%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			row.Date.Format("2006-01-02"),
-			row.Time,
-			row.EstimatedTime,
-			row.ActualTime,
-			row.Project,
-			row.TaskName,
-			row.TaskNote,
-		)
-	}
-}
-
-// ---------------------------------------------------------
-// ヘルパー関数
-// ---------------------------------------------------------
-
-// loadHolidays は holiday.toml を読み込み map に格納する
-func loadHolidays(filename string) {
-	var config HolidayConfig
-	if _, err := toml.DecodeFile(filename, &config); err != nil {
-		log.Fatalf("エラー: 祝日ファイル(%s)の読み込みに失敗しました: %v", filename, err)
-	}
-
-	holidayMap = make(map[string]bool)
-	for _, h := range config.Holidays {
-		// 念のため日付フォーマットを正規化またはチェックすることも可能
-		holidayMap[h.Date] = true
-	}
-}
-
-// loadTasks は task.toml を読み込み構造体のスライスを返す
-func loadTasks(filename string) []TaskTemplate {
-	var config TaskConfig
-	if _, err := toml.DecodeFile(filename, &config); err != nil {
-		log.Fatalf("エラー: タスクファイル(%s)の読み込みに失敗しました: %v", filename, err)
-	}
-	return config.Tasks
-}
-
-// isHoliday は指定日が祝日かどうかを判定する
-func isHoliday(t time.Time) bool {
-	dateStr := t.Format("2006-01-02")
-	return holidayMap[dateStr]
-}
-
-// isWeekend は指定日が土日かどうかを判定する
-func isWeekend(t time.Time) bool {
-	wd := t.Weekday()
-	return wd == time.Saturday || wd == time.Sunday
-}
-
-// isBusinessDay は平日（土日祝以外）かどうかを判定する
-func isBusinessDay(t time.Time) bool {
-	return !isWeekend(t) && !isHoliday(t)
-}
-
-// getPreviousBusinessDay は指定日の「1日前の平日」を再帰的/ループで探索する
-func getPreviousBusinessDay(baseDate time.Time) time.Time {
-	d := baseDate.AddDate(0, 0, -1) // まず1日戻る
-	for {
-		if isBusinessDay(d) {
-			return d
-		}
-		// 土日祝ならさらに戻る
-		d = d.AddDate(0, 0, -1)
-	}
-}
+/*
+# タスク一覧の繰り返しタスク作成
+*/
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/yo-kondo/task_generator/bizday"
+)
+
+// ---------------------------------------------------------
+// データ構造定義
+// ---------------------------------------------------------
+
+// TaskConfig は task.toml の構造を定義
+type TaskConfig struct {
+	Tasks []TaskTemplate `toml:"task"`
+}
+
+type TaskTemplate struct {
+	Repeat        string   `toml:"repeat"`
+	Time          string   `toml:"time"`
+	EstimatedTime string   `toml:"estimated_time"`
+	Project       string   `toml:"project"`
+	TaskName      string   `toml:"task_name"`
+	TaskNote      string   `toml:"task_note"`
+	CarryOver     bool     `toml:"carry_over"` // 月末までに完了しなければ翌月の最初の営業日に繰り越す
+	DependsOn     []string `toml:"depends_on"` // 依存タスクの最新インスタンスが完了するまで生成しない
+}
+
+// OutputRow は出力する1行分のデータを保持
+type OutputRow struct {
+	Date          time.Time
+	Time          string
+	EstimatedTime string
+	ActualTime    string // 常に空白
+	Project       string
+	TaskName      string // 表示名（繰越タスクは "[繰越]" 等の接頭辞付き）
+	BaseTaskName  string // task.toml の task_name そのもの。carry_over/depends_on の突き合わせに使う
+	TaskNote      string
+}
+
+// ---------------------------------------------------------
+// グローバル定数・変数
+// ---------------------------------------------------------
+
+// 曜日文字列と time.Weekday のマッピング
+var weekdayMap = map[string]time.Weekday{
+	"日曜日": time.Sunday,
+	"月曜日": time.Monday,
+	"火曜日": time.Tuesday,
+	"水曜日": time.Wednesday,
+	"木曜日": time.Thursday,
+	"金曜日": time.Friday,
+	"土曜日": time.Saturday,
+}
+
+// outputFormat は --format フラグで指定される出力形式（デフォルトは従来どおり tsv）
+var outputFormat = "tsv"
+
+// outputPath は --output フラグで指定される出力先ファイルパス（未指定なら標準出力）
+var outputPath = ""
+
+// calendar は選択中の HolidayProvider と週末の定義を束ねた、全ての日付判定の基点
+// runGenerate 内で、設定読み込み後に組み立てられる
+var calendar *bizday.Calendar
+
+// ---------------------------------------------------------
+// メイン処理
+// ---------------------------------------------------------
+
+// main はサブコマンド(generate/mark-done/list-open/status)を振り分ける
+// 後方互換のため、サブコマンドを省略して年月(yyyymm)だけを渡した場合は generate として扱う
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("エラー: サブコマンドまたは引数に年月(yyyymm)を指定してください。\n例: go run main.go generate 202511")
+	}
+
+	switch os.Args[1] {
+	case "generate":
+		runGenerate(os.Args[2:])
+	case "mark-done":
+		runMarkDone(os.Args[2:])
+	case "list-open":
+		runListOpen(os.Args[2:])
+	case "status":
+		runStatus(os.Args[2:])
+	default:
+		runGenerate(os.Args[1:])
+	}
+}
+
+// ---------------------------------------------------------
+// ヘルパー関数
+// ---------------------------------------------------------
+
+// parseArgs はコマンドライン引数を解析する
+// --rollback=forward|backward|skip、--anchor=YYYY-MM-DD、--format=tsv|csv|json|ics|md、
+// --output=FILE を受け付け、残った最初の位置引数を対象年月(yyyymm)として返す
+func parseArgs(args []string) (string, error) {
+	var targetYMStr string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--rollback="):
+			mode, err := parseRollbackMode(strings.TrimPrefix(arg, "--rollback="))
+			if err != nil {
+				return "", err
+			}
+			rollbackMode = mode
+
+		case strings.HasPrefix(arg, "--anchor="):
+			anchor, err := time.Parse("2006-01-02", strings.TrimPrefix(arg, "--anchor="))
+			if err != nil {
+				return "", fmt.Errorf("--anchor の形式が不正です(YYYY-MM-DD形式で指定してください): %w", err)
+			}
+			biweeklyAnchor = &anchor
+
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimPrefix(arg, "--format=")
+
+		case strings.HasPrefix(arg, "--output="):
+			outputPath = strings.TrimPrefix(arg, "--output=")
+
+		case strings.HasPrefix(arg, "--"):
+			return "", fmt.Errorf("未対応のオプションです: %s", arg)
+
+		default:
+			if targetYMStr == "" {
+				targetYMStr = arg
+			}
+		}
+	}
+	if targetYMStr == "" {
+		return "", fmt.Errorf("引数に年月(yyyymm)を指定してください。\n例: go run main.go 202511")
+	}
+	return targetYMStr, nil
+}
+
+// loadTasks は task.toml を読み込み構造体のスライスを返す
+func loadTasks(filename string) []TaskTemplate {
+	var config TaskConfig
+	if _, err := toml.DecodeFile(filename, &config); err != nil {
+		log.Fatalf("エラー: タスクファイル(%s)の読み込みに失敗しました: %v", filename, err)
+	}
+	return config.Tasks
+}
+
+// isHoliday は指定日が祝日かどうかを判定する（calendar 経由）
+func isHoliday(t time.Time) bool {
+	if calendar == nil {
+		return false
+	}
+	return calendar.IsHoliday(t)
+}
+
+// isWeekend は指定日が週末かどうかを判定する（calendar 経由。未設定時は土日をデフォルトとする）
+func isWeekend(t time.Time) bool {
+	if calendar == nil {
+		wd := t.Weekday()
+		return wd == time.Saturday || wd == time.Sunday
+	}
+	return calendar.IsWeekend(t)
+}
+
+// isBusinessDay は週末でも祝日でもない日かどうかを判定する（calendar 経由）
+func isBusinessDay(t time.Time) bool {
+	if calendar == nil {
+		return !isWeekend(t) && !isHoliday(t)
+	}
+	return calendar.IsBusinessDay(t)
+}
+
+// getPreviousBusinessDay は指定日の直前の営業日を返す（calendar 経由）
+func getPreviousBusinessDay(baseDate time.Time) time.Time {
+	return calendar.PreviousBusinessDay(baseDate)
+}
+
+// getNextBusinessDay は指定日の直後の営業日を返す（calendar 経由）
+func getNextBusinessDay(baseDate time.Time) time.Time {
+	return calendar.NextBusinessDay(baseDate)
+}
+
+// monthFirstBusinessDay は year年month月の最初の営業日を返す（calendar 経由）
+func monthFirstBusinessDay(year int, month time.Month) time.Time {
+	if calendar == nil {
+		d := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+		for !isBusinessDay(d) {
+			d = d.AddDate(0, 0, 1)
+		}
+		return d
+	}
+	return calendar.NthBusinessDayOfMonth(year, month, 1)
+}
+
+// monthLastBusinessDay は year年month月の最後の営業日を返す（calendar 経由）
+func monthLastBusinessDay(year int, month time.Month) time.Time {
+	if calendar == nil {
+		d := time.Date(year, month+1, 1, 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+		for !isBusinessDay(d) {
+			d = d.AddDate(0, 0, -1)
+		}
+		return d
+	}
+	return calendar.LastBusinessDayOfMonth(year, month)
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------
+// ロールバック設定（生成日が非営業日に重なった場合の調整方法）
+// ---------------------------------------------------------
+
+// RollbackMode は生成日が土日祝と重なった場合の調整方法を表す
+type RollbackMode string
+
+const (
+	RollbackForward  RollbackMode = "forward"  // 翌営業日にずらす
+	RollbackBackward RollbackMode = "backward" // 前営業日にずらす（従来の挙動）
+	RollbackSkip     RollbackMode = "skip"     // その回を生成しない
+)
+
+// rollbackMode は --rollback フラグで指定される調整方法（デフォルトは従来どおり backward）
+var rollbackMode = RollbackBackward
+
+// parseRollbackMode は --rollback の値を検証して RollbackMode に変換する
+func parseRollbackMode(s string) (RollbackMode, error) {
+	switch RollbackMode(s) {
+	case RollbackForward, RollbackBackward, RollbackSkip:
+		return RollbackMode(s), nil
+	default:
+		return "", fmt.Errorf("未対応の --rollback 値です: %s (forward/backward/skip のいずれかを指定してください)", s)
+	}
+}
+
+// adjustForRollback は d が非営業日であれば rollbackMode に従って調整する
+// rollbackMode が skip で d が非営業日の場合は ok=false を返し、呼び出し元は生成を見送る
+func adjustForRollback(d time.Time) (adjusted time.Time, ok bool) {
+	if isBusinessDay(d) {
+		return d, true
+	}
+	switch rollbackMode {
+	case RollbackForward:
+		return getNextBusinessDay(d), true
+	case RollbackSkip:
+		return d, false
+	default: // RollbackBackward
+		return getPreviousBusinessDay(d), true
+	}
+}
+
+// ---------------------------------------------------------
+// RecurrenceRule: 繰り返し設定1件から生成日一覧を求めるインタフェース
+// ---------------------------------------------------------
+
+// RecurrenceRule は task.toml の repeat 設定1件を表し、対象月の中で実際に
+// タスクを生成すべき日付の一覧を求める
+type RecurrenceRule interface {
+	// Expand は firstDay から lastDay までの範囲（両端を含む）で、
+	// このルールに従って生成される日付一覧を返す
+	Expand(firstDay, lastDay time.Time) []time.Time
+}
+
+// DailyRule は「毎日」：土日祝を除く平日すべて
+type DailyRule struct{}
+
+func (r DailyRule) Expand(firstDay, lastDay time.Time) []time.Time {
+	var dates []time.Time
+	for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
+		if isBusinessDay(d) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// MonthStartRule は「月初」：月の最初の平日
+type MonthStartRule struct{}
+
+func (r MonthStartRule) Expand(firstDay, lastDay time.Time) []time.Time {
+	d := monthFirstBusinessDay(firstDay.Year(), firstDay.Month())
+	if d.Before(firstDay) || d.After(lastDay) {
+		return nil
+	}
+	return []time.Time{d}
+}
+
+// MonthEndRule は「月末」：月の最後の平日
+type MonthEndRule struct{}
+
+func (r MonthEndRule) Expand(firstDay, lastDay time.Time) []time.Time {
+	d := monthLastBusinessDay(lastDay.Year(), lastDay.Month())
+	if d.Before(firstDay) || d.After(lastDay) {
+		return nil
+	}
+	return []time.Time{d}
+}
+
+// WeeklyRule は「毎週◯曜日」（カンマ区切りで複数曜日指定も可）：
+// 該当曜日が祝日と重なる場合は rollbackMode に従って調整する
+type WeeklyRule struct {
+	Weekdays []time.Weekday
+}
+
+func (r WeeklyRule) Expand(firstDay, lastDay time.Time) []time.Time {
+	var dates []time.Time
+	for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
+		for _, wd := range r.Weekdays {
+			if d.Weekday() != wd {
+				continue
+			}
+			if finalDate, ok := adjustForRollback(d); ok {
+				dates = append(dates, finalDate)
+			}
+			break
+		}
+	}
+	return dates
+}
+
+// NthWeekdayRule は「第N◯曜日」「第最終◯曜日」：月内のN番目（または最終）の指定曜日
+type NthWeekdayRule struct {
+	Weekday time.Weekday
+	N       int  // Last が false のときのみ使用（1始まり）
+	Last    bool // 「第最終」指定かどうか
+}
+
+func (r NthWeekdayRule) Expand(firstDay, lastDay time.Time) []time.Time {
+	var target time.Time
+	if r.Last {
+		d := lastDay
+		for d.Weekday() != r.Weekday {
+			d = d.AddDate(0, 0, -1)
+		}
+		target = d
+	} else {
+		count := 0
+		d := firstDay
+		for !d.After(lastDay) {
+			if d.Weekday() == r.Weekday {
+				count++
+				if count == r.N {
+					target = d
+					break
+				}
+			}
+			d = d.AddDate(0, 0, 1)
+		}
+		if target.IsZero() {
+			return nil
+		}
+	}
+	if finalDate, ok := adjustForRollback(target); ok {
+		return []time.Time{finalDate}
+	}
+	return nil
+}
+
+// BiweeklyRule は「隔週◯曜日」：anchor（未指定なら対象月内で最初に現れる指定曜日）を
+// 起点として2週間おきに生成する
+type BiweeklyRule struct {
+	Weekday time.Weekday
+	Anchor  *time.Time
+}
+
+func (r BiweeklyRule) Expand(firstDay, lastDay time.Time) []time.Time {
+	anchor := firstDay
+	if r.Anchor != nil {
+		anchor = *r.Anchor
+	}
+	for anchor.Weekday() != r.Weekday {
+		anchor = anchor.AddDate(0, 0, 1)
+	}
+
+	// anchor を基準に、対象月の範囲に収まる隔週の日付まで進める/戻す
+	d := anchor
+	for d.After(firstDay) {
+		d = d.AddDate(0, 0, -14)
+	}
+	for d.Before(firstDay) {
+		d = d.AddDate(0, 0, 14)
+	}
+
+	var dates []time.Time
+	for !d.After(lastDay) {
+		if finalDate, ok := adjustForRollback(d); ok {
+			dates = append(dates, finalDate)
+		}
+		d = d.AddDate(0, 0, 14)
+	}
+	return dates
+}
+
+// MonthDayRule は「毎月N日」：月の指定日（月末を超える場合はスキップ）
+type MonthDayRule struct {
+	Day int
+}
+
+func (r MonthDayRule) Expand(firstDay, lastDay time.Time) []time.Time {
+	year, month, _ := firstDay.Date()
+	d := time.Date(year, month, r.Day, 0, 0, 0, 0, firstDay.Location())
+	if d.Month() != month {
+		// 指定日がその月に存在しない（例: 2月30日）
+		return nil
+	}
+	finalDate, ok := adjustForRollback(d)
+	if !ok || finalDate.Before(firstDay) || finalDate.After(lastDay) {
+		// ロールバック先が前月/翌月にはみ出す場合は、対象月の範囲外として扱い生成しない
+		// （はみ出した日付は、はみ出した先の月の生成時に別途処理される）
+		return nil
+	}
+	return []time.Time{finalDate}
+}
+
+// IntervalRule は「毎N日」：firstDay を起点としたN日おきの固定間隔
+type IntervalRule struct {
+	IntervalDays int
+}
+
+func (r IntervalRule) Expand(firstDay, lastDay time.Time) []time.Time {
+	var dates []time.Time
+	for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, r.IntervalDays) {
+		finalDate, ok := adjustForRollback(d)
+		if !ok || finalDate.Before(firstDay) || finalDate.After(lastDay) {
+			// ロールバック先が前月/翌月にはみ出す場合は、対象月の範囲外として扱い生成しない
+			// （はみ出した日付は、はみ出した先の月の生成時に別途処理される）
+			continue
+		}
+		dates = append(dates, finalDate)
+	}
+	return dates
+}
+
+// ---------------------------------------------------------
+// パース処理
+// ---------------------------------------------------------
+
+// shortWeekdayMap は「第2月曜日」「隔週水曜日」のような1文字の曜日表記用マッピング
+var shortWeekdayMap = map[string]time.Weekday{
+	"日": time.Sunday,
+	"月": time.Monday,
+	"火": time.Tuesday,
+	"水": time.Wednesday,
+	"木": time.Thursday,
+	"金": time.Friday,
+	"土": time.Saturday,
+}
+
+// weekdaySortOrder は日曜日を最後に回す並び順（gte の Weekdays ヘルパーに倣う）
+var weekdaySortOrder = map[time.Weekday]int{
+	time.Monday:    0,
+	time.Tuesday:   1,
+	time.Wednesday: 2,
+	time.Thursday:  3,
+	time.Friday:    4,
+	time.Saturday:  5,
+	time.Sunday:    6,
+}
+
+// sortWeekdaysSundayLast は曜日一覧を月曜始まり・日曜終わりの順に重複なく並べ替える
+func sortWeekdaysSundayLast(weekdays []time.Weekday) []time.Weekday {
+	seen := make(map[time.Weekday]bool)
+	unique := make([]time.Weekday, 0, len(weekdays))
+	for _, wd := range weekdays {
+		if !seen[wd] {
+			seen[wd] = true
+			unique = append(unique, wd)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		return weekdaySortOrder[unique[i]] < weekdaySortOrder[unique[j]]
+	})
+	return unique
+}
+
+var (
+	reNthWeekday = regexp.MustCompile(`^第(\d+|最終)(.)曜日$`)
+	reBiweekly   = regexp.MustCompile(`^隔週(.)曜日$`)
+	reMonthDay   = regexp.MustCompile(`^毎月(\d+)日$`)
+	reInterval   = regexp.MustCompile(`^毎(\d+)日$`)
+)
+
+// parseRecurrenceRule は task.toml の repeat 文字列を解析し、対応する RecurrenceRule を返す
+func parseRecurrenceRule(repeat string) (RecurrenceRule, error) {
+	switch {
+	case repeat == "毎日":
+		return DailyRule{}, nil
+
+	case repeat == "月初":
+		return MonthStartRule{}, nil
+
+	case repeat == "月末":
+		return MonthEndRule{}, nil
+
+	case reNthWeekday.MatchString(repeat):
+		m := reNthWeekday.FindStringSubmatch(repeat)
+		wd, ok := shortWeekdayMap[m[2]]
+		if !ok {
+			return nil, fmt.Errorf("未知の曜日指定です: %s", repeat)
+		}
+		if m[1] == "最終" {
+			return NthWeekdayRule{Weekday: wd, Last: true}, nil
+		}
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("不正な第N指定です: %s", repeat)
+		}
+		return NthWeekdayRule{Weekday: wd, N: n}, nil
+
+	case reBiweekly.MatchString(repeat):
+		m := reBiweekly.FindStringSubmatch(repeat)
+		wd, ok := shortWeekdayMap[m[1]]
+		if !ok {
+			return nil, fmt.Errorf("未知の曜日指定です: %s", repeat)
+		}
+		return BiweeklyRule{Weekday: wd, Anchor: biweeklyAnchor}, nil
+
+	case strings.HasPrefix(repeat, "毎週"):
+		weekdayPart := strings.TrimPrefix(repeat, "毎週")
+		var weekdays []time.Weekday
+		for _, token := range strings.Split(weekdayPart, ",") {
+			wd, ok := weekdayMap[token]
+			if !ok {
+				wd, ok = shortWeekdayMap[token]
+			}
+			if !ok {
+				return nil, fmt.Errorf("未知の曜日指定です: %s (指定: %s)", token, repeat)
+			}
+			weekdays = append(weekdays, wd)
+		}
+		if len(weekdays) == 0 {
+			return nil, fmt.Errorf("曜日が指定されていません: %s", repeat)
+		}
+		return WeeklyRule{Weekdays: sortWeekdaysSundayLast(weekdays)}, nil
+
+	case reMonthDay.MatchString(repeat):
+		m := reMonthDay.FindStringSubmatch(repeat)
+		day, err := strconv.Atoi(m[1])
+		if err != nil || day < 1 || day > 31 {
+			return nil, fmt.Errorf("不正な日指定です: %s", repeat)
+		}
+		return MonthDayRule{Day: day}, nil
+
+	case reInterval.MatchString(repeat):
+		m := reInterval.FindStringSubmatch(repeat)
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("不正な間隔指定です: %s", repeat)
+		}
+		return IntervalRule{IntervalDays: n}, nil
+
+	default:
+		return nil, fmt.Errorf("未対応の繰り返し設定です: %s", repeat)
+	}
+}
+
+// biweeklyAnchor は --anchor フラグで指定された隔週計算の起点日（未指定なら nil）
+var biweeklyAnchor *time.Time
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yo-kondo/task_generator/bizday"
+)
+
+// mapHolidays はテスト用の固定祝日セット（bizday.HolidaySource を満たす）
+type mapHolidays map[string]bool
+
+func (m mapHolidays) IsHoliday(t time.Time) bool {
+	return m[t.Format("2006-01-02")]
+}
+
+// withTestCalendar はテスト中だけ calendar と rollbackMode を差し替え、終了時に元へ戻す
+func withTestCalendar(t *testing.T, holidays mapHolidays, mode RollbackMode) {
+	t.Helper()
+	origCalendar := calendar
+	origMode := rollbackMode
+	calendar = bizday.NewCalendar(holidays, nil)
+	rollbackMode = mode
+	t.Cleanup(func() {
+		calendar = origCalendar
+		rollbackMode = origMode
+	})
+}
+
+func d(y int, m time.Month, day int) time.Time {
+	return time.Date(y, m, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	cases := []struct {
+		name    string
+		repeat  string
+		want    RecurrenceRule
+		wantErr bool
+	}{
+		{"毎日", "毎日", DailyRule{}, false},
+		{"月初", "月初", MonthStartRule{}, false},
+		{"月末", "月末", MonthEndRule{}, false},
+		{"第N曜日", "第2月曜日", NthWeekdayRule{Weekday: time.Monday, N: 2}, false},
+		{"第最終曜日", "第最終金曜日", NthWeekdayRule{Weekday: time.Friday, Last: true}, false},
+		{"隔週", "隔週水曜日", BiweeklyRule{Weekday: time.Wednesday}, false},
+		{"毎週単数", "毎週月曜日", WeeklyRule{Weekdays: []time.Weekday{time.Monday}}, false},
+		{
+			"毎週複数は日曜日が最後に並び替わる",
+			"毎週日曜日,月曜日",
+			WeeklyRule{Weekdays: []time.Weekday{time.Monday, time.Sunday}},
+			false,
+		},
+		{"毎月N日", "毎月25日", MonthDayRule{Day: 25}, false},
+		{"毎N日", "毎3日", IntervalRule{IntervalDays: 3}, false},
+		{"未知の曜日", "第2炎曜日", nil, true},
+		{"未知の繰り返し", "よくわからない設定", nil, true},
+		{"日が範囲外", "毎月32日", nil, true},
+		{"間隔が0", "毎0日", nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parseRecurrenceRule(c.repeat)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("parseRecurrenceRule(%q) error = nil, wantErr", c.repeat)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRecurrenceRule(%q) error = %v", c.repeat, err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("parseRecurrenceRule(%q) = %#v, want %#v", c.repeat, got, c.want)
+			}
+		})
+	}
+}
+
+func TestNthWeekdayRuleExpand(t *testing.T) {
+	withTestCalendar(t, mapHolidays{}, RollbackBackward)
+
+	// 2026年1月: 月曜日は 5,12,19,26日
+	rule := NthWeekdayRule{Weekday: time.Monday, N: 2}
+	got := rule.Expand(d(2026, 1, 1), d(2026, 1, 31))
+	want := []time.Time{d(2026, 1, 12)}
+	if len(got) != 1 || !got[0].Equal(want[0]) {
+		t.Errorf("第2月曜日 = %v, want %v", got, want)
+	}
+
+	// 第最終金曜日: 2026年1月の最終金曜日は30日
+	lastRule := NthWeekdayRule{Weekday: time.Friday, Last: true}
+	got = lastRule.Expand(d(2026, 1, 1), d(2026, 1, 31))
+	want = []time.Time{d(2026, 1, 30)}
+	if len(got) != 1 || !got[0].Equal(want[0]) {
+		t.Errorf("第最終金曜日 = %v, want %v", got, want)
+	}
+
+	// 範囲内に存在しないN番目は空を返す（例: 第5月曜日が無い月）
+	missing := NthWeekdayRule{Weekday: time.Monday, N: 5}
+	if got := missing.Expand(d(2026, 1, 1), d(2026, 1, 31)); got != nil {
+		t.Errorf("第5月曜日(存在しない) = %v, want nil", got)
+	}
+}
+
+func TestBiweeklyRuleExpand(t *testing.T) {
+	withTestCalendar(t, mapHolidays{}, RollbackBackward)
+
+	anchor := d(2026, 1, 7) // 水曜日
+	rule := BiweeklyRule{Weekday: time.Wednesday, Anchor: &anchor}
+	got := rule.Expand(d(2026, 1, 1), d(2026, 1, 31))
+	want := []time.Time{d(2026, 1, 7), d(2026, 1, 21)}
+	if len(got) != len(want) {
+		t.Fatalf("隔週水曜日 = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("隔週水曜日[%d] = %s, want %s", i, got[i].Format("2006-01-02"), want[i].Format("2006-01-02"))
+		}
+	}
+}
+
+func TestWeeklyRuleExpandWithRollback(t *testing.T) {
+	// 2026-01-12(月)を祝日にして、各ロールバックモードの挙動を確認する
+	holidays := mapHolidays{"2026-01-12": true}
+
+	t.Run("backward", func(t *testing.T) {
+		withTestCalendar(t, holidays, RollbackBackward)
+		rule := WeeklyRule{Weekdays: []time.Weekday{time.Monday}}
+		got := rule.Expand(d(2026, 1, 1), d(2026, 1, 31))
+		// 1/12(月)は祝日のため、前営業日の1/9(金)に繰り下がる
+		want := []time.Time{d(2026, 1, 5), d(2026, 1, 9), d(2026, 1, 19), d(2026, 1, 26)}
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range want {
+			if !got[i].Equal(want[i]) {
+				t.Errorf("[%d] = %s, want %s", i, got[i].Format("2006-01-02"), want[i].Format("2006-01-02"))
+			}
+		}
+	})
+
+	t.Run("forward", func(t *testing.T) {
+		withTestCalendar(t, holidays, RollbackForward)
+		rule := WeeklyRule{Weekdays: []time.Weekday{time.Monday}}
+		got := rule.Expand(d(2026, 1, 1), d(2026, 1, 31))
+		want := []time.Time{d(2026, 1, 5), d(2026, 1, 13), d(2026, 1, 19), d(2026, 1, 26)}
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range want {
+			if !got[i].Equal(want[i]) {
+				t.Errorf("[%d] = %s, want %s", i, got[i].Format("2006-01-02"), want[i].Format("2006-01-02"))
+			}
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		withTestCalendar(t, holidays, RollbackSkip)
+		rule := WeeklyRule{Weekdays: []time.Weekday{time.Monday}}
+		got := rule.Expand(d(2026, 1, 1), d(2026, 1, 31))
+		want := []time.Time{d(2026, 1, 5), d(2026, 1, 19), d(2026, 1, 26)}
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range want {
+			if !got[i].Equal(want[i]) {
+				t.Errorf("[%d] = %s, want %s", i, got[i].Format("2006-01-02"), want[i].Format("2006-01-02"))
+			}
+		}
+	})
+}
+
+func TestMonthDayRuleExpand(t *testing.T) {
+	withTestCalendar(t, mapHolidays{}, RollbackBackward)
+
+	// 2月30日のような存在しない日は生成しない
+	rule := MonthDayRule{Day: 30}
+	if got := rule.Expand(d(2026, 2, 1), d(2026, 2, 28)); got != nil {
+		t.Errorf("MonthDayRule(30).Expand(2026-02) = %v, want nil", got)
+	}
+
+	// 2026-02-15は日曜日のため、backwardロールバックで前営業日の2/13(金)になる
+	normal := MonthDayRule{Day: 15}
+	got := normal.Expand(d(2026, 2, 1), d(2026, 2, 28))
+	want := d(2026, 2, 13)
+	if len(got) != 1 || !got[0].Equal(want) {
+		t.Errorf("MonthDayRule(15).Expand(2026-02) = %v, want [%s]", got, want.Format("2006-01-02"))
+	}
+}
+
+// TestMonthDayRuleExpandClampsRollbackOutOfMonth は、月初日が土日などの非営業日で
+// backwardロールバックの結果が前月にはみ出す場合、その回を生成しないことを確認する。
+// はみ出した分を生成してしまうと、前月側の生成でも同じ日付が既に出力されており、
+// 同一タスクインスタンスが2つの月にまたがって二重に出力されてしまう
+func TestMonthDayRuleExpandClampsRollbackOutOfMonth(t *testing.T) {
+	withTestCalendar(t, mapHolidays{}, RollbackBackward)
+
+	// 2026-08-01(土)は8月の月初日。backwardロールバックすると7/31(金)にずれるが、
+	// これは8月の範囲外なので生成しない
+	rule := MonthDayRule{Day: 1}
+	got := rule.Expand(d(2026, 8, 1), d(2026, 8, 31))
+	if got != nil {
+		t.Errorf("MonthDayRule(1).Expand(2026-08) = %v, want nil（7月にはみ出すロールバック結果は生成しない）", got)
+	}
+}
+
+func TestIntervalRuleExpand(t *testing.T) {
+	withTestCalendar(t, mapHolidays{}, RollbackBackward)
+
+	// 1/11(日)と1/31(土)は週末のため、backwardロールバックでそれぞれ前営業日にずれる
+	rule := IntervalRule{IntervalDays: 10}
+	got := rule.Expand(d(2026, 1, 1), d(2026, 1, 31))
+	want := []time.Time{d(2026, 1, 1), d(2026, 1, 9), d(2026, 1, 21), d(2026, 1, 30)}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("[%d] = %s, want %s", i, got[i].Format("2006-01-02"), want[i].Format("2006-01-02"))
+		}
+	}
+}
+
+// TestIntervalRuleExpandClampsRollbackOutOfMonth は、月末日が土日などの非営業日で
+// backwardロールバックの結果が前月にはみ出す場合、その回を生成しないことを確認する
+func TestIntervalRuleExpandClampsRollbackOutOfMonth(t *testing.T) {
+	withTestCalendar(t, mapHolidays{}, RollbackBackward)
+
+	// 2026-08-01(土)起点の5日おき: 8/1(土,範囲外にロールバック),8/6,8/11,...
+	rule := IntervalRule{IntervalDays: 5}
+	got := rule.Expand(d(2026, 8, 1), d(2026, 8, 31))
+	for _, date := range got {
+		if date.Before(d(2026, 8, 1)) || date.After(d(2026, 8, 31)) {
+			t.Errorf("範囲外の日付が生成された: %s", date.Format("2006-01-02"))
+		}
+	}
+	if len(got) == 0 {
+		t.Fatal("範囲内の生成日が1件も無かった")
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// statePath は --state フラグで指定される状態ファイルのパス（デフォルトは ./state.json）
+var statePath = "./state.json"
+
+// インスタンスの状態を表す定数
+const (
+	StatusPlanned = "planned" // 生成済み・未完了
+	StatusDone    = "done"    // 完了
+	StatusSkipped = "skipped" // 見送り
+	StatusCarried = "carried" // 翌月に繰り越し済み
+)
+
+// TaskInstance は生成されたタスク1件分の永続状態を表す
+type TaskInstance struct {
+	ID            string `json:"id"`
+	TaskName      string `json:"task_name"`      // 表示名（繰越タスクは "[繰越]" 等の接頭辞付き）
+	BaseTaskName  string `json:"base_task_name"` // task.toml の task_name そのもの。carry_over/depends_on の突き合わせに使う
+	Project       string `json:"project"`
+	Date          string `json:"date"` // yyyy-mm-dd
+	Status        string `json:"status"`
+	LastCompleted string `json:"last_completed,omitempty"` // yyyy-mm-dd
+}
+
+// State は全タスクインスタンスの永続状態一式
+type State struct {
+	Instances map[string]*TaskInstance `json:"instances"`
+}
+
+// loadState は path から状態を読み込む。ファイルが存在しない場合は空の状態を返す
+func loadState(path string) (*State, error) {
+	state := &State{Instances: make(map[string]*TaskInstance)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("状態ファイル(%s)の読み込みに失敗しました: %w", path, err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("状態ファイル(%s)の解析に失敗しました: %w", path, err)
+	}
+	if state.Instances == nil {
+		state.Instances = make(map[string]*TaskInstance)
+	}
+	return state, nil
+}
+
+// saveState は状態を path へJSONとして書き込む
+func saveState(path string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("状態のエンコードに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("状態ファイル(%s)の書き込みに失敗しました: %w", path, err)
+	}
+	return nil
+}
+
+// taskInstanceID は (日付, プロジェクト, タスク名) から一意なインスタンスIDを決定する
+func taskInstanceID(date, project, taskName string) string {
+	return fmt.Sprintf("%s:%s:%s", date, project, taskName)
+}
+
+// registerPlannedInstance は生成された出力行を状態に記録する
+// 同じIDのインスタンスが既にあれば（mark-done 済みの場合も含め）何もしない
+func registerPlannedInstance(state *State, row OutputRow) {
+	dateStr := row.Date.Format("2006-01-02")
+	id := taskInstanceID(dateStr, row.Project, row.TaskName)
+	if _, exists := state.Instances[id]; exists {
+		return
+	}
+	state.Instances[id] = &TaskInstance{
+		ID:           id,
+		TaskName:     row.TaskName,
+		BaseTaskName: row.BaseTaskName,
+		Project:      row.Project,
+		Date:         dateStr,
+		Status:       StatusPlanned,
+	}
+}
+
+// latestInstanceForTask は指定タスク名（BaseTaskName）の中で最新の日付を持つインスタンスを返す
+// （無ければ nil）。繰越タスクは TaskName に "[繰越]" 等の接頭辞が付くため、突き合わせは
+// 接頭辞の影響を受けない BaseTaskName で行う
+func latestInstanceForTask(state *State, taskName string) *TaskInstance {
+	var latest *TaskInstance
+	for _, inst := range state.Instances {
+		if inst.BaseTaskName != taskName {
+			continue
+		}
+		if latest == nil || inst.Date > latest.Date {
+			latest = inst
+		}
+	}
+	return latest
+}
+
+// dependenciesSatisfied は dependsOn で指定された全タスクの最新インスタンスが
+// done になっているかどうかを判定する（インスタンスが1件も無い依存タスクは未達とみなす）
+func dependenciesSatisfied(state *State, dependsOn []string) bool {
+	for _, depName := range dependsOn {
+		latest := latestInstanceForTask(state, depName)
+		if latest == nil || latest.Status != StatusDone {
+			return false
+		}
+	}
+	return true
+}
+
+// pendingInstanceInRange は [from, to] （yyyy-mm-dd, 両端含む）の範囲にある
+// 指定タスク（BaseTaskName）の planned インスタンスのうち最新のものを返す（無ければ nil）。
+// 繰越で生成されたインスタンスも BaseTaskName で突き合わせるため、再度繰り越す対象として見つかる
+func pendingInstanceInRange(state *State, project, taskName, from, to string) *TaskInstance {
+	var pending *TaskInstance
+	for _, inst := range state.Instances {
+		if inst.BaseTaskName != taskName || inst.Project != project {
+			continue
+		}
+		if inst.Status != StatusPlanned {
+			continue
+		}
+		if inst.Date < from || inst.Date > to {
+			continue
+		}
+		if pending == nil || inst.Date > pending.Date {
+			pending = inst
+		}
+	}
+	return pending
+}
+
+// sortedInstances は表示用に日付順へ安定ソートしたインスタンス一覧を返す
+func sortedInstances(state *State) []*TaskInstance {
+	instances := make([]*TaskInstance, 0, len(state.Instances))
+	for _, inst := range state.Instances {
+		instances = append(instances, inst)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Date == instances[j].Date {
+			return instances[i].ID < instances[j].ID
+		}
+		return instances[i].Date < instances[j].Date
+	})
+	return instances
+}
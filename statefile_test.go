@@ -0,0 +1,137 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegisterPlannedInstanceIsIdempotent(t *testing.T) {
+	state := &State{Instances: make(map[string]*TaskInstance)}
+	row := OutputRow{
+		Date:         d(2026, 1, 30),
+		Project:      "経理",
+		TaskName:     "月次レポート",
+		BaseTaskName: "月次レポート",
+	}
+	registerPlannedInstance(state, row)
+
+	id := taskInstanceID("2026-01-30", "経理", "月次レポート")
+	state.Instances[id].Status = StatusDone
+
+	// 同じ行を再登録しても既存のステータス(done)は上書きされない
+	registerPlannedInstance(state, row)
+	if state.Instances[id].Status != StatusDone {
+		t.Errorf("registerPlannedInstance が既存インスタンスを上書きした: status = %s", state.Instances[id].Status)
+	}
+}
+
+func TestLatestInstanceForTaskMatchesBaseTaskName(t *testing.T) {
+	state := &State{Instances: make(map[string]*TaskInstance)}
+	registerPlannedInstance(state, OutputRow{
+		Date: d(2026, 1, 30), Project: "経理", TaskName: "月次レポート", BaseTaskName: "月次レポート",
+	})
+	registerPlannedInstance(state, OutputRow{
+		Date: d(2026, 2, 2), Project: "経理", TaskName: "[繰越]月次レポート", BaseTaskName: "月次レポート",
+	})
+
+	latest := latestInstanceForTask(state, "月次レポート")
+	if latest == nil {
+		t.Fatal("latestInstanceForTask が見つからなかった")
+	}
+	if latest.Date != "2026-02-02" {
+		t.Errorf("latestInstanceForTask の Date = %s, want 2026-02-02", latest.Date)
+	}
+
+	if got := latestInstanceForTask(state, "存在しないタスク"); got != nil {
+		t.Errorf("未知のタスク名で nil 以外が返った: %+v", got)
+	}
+}
+
+func TestDependenciesSatisfied(t *testing.T) {
+	state := &State{Instances: make(map[string]*TaskInstance)}
+
+	// インスタンスが1件も無い依存タスクは未達
+	if dependenciesSatisfied(state, []string{"月次レポート"}) {
+		t.Error("インスタンスが存在しないのに依存が満たされたと判定された")
+	}
+
+	registerPlannedInstance(state, OutputRow{
+		Date: d(2026, 1, 30), Project: "経理", TaskName: "月次レポート", BaseTaskName: "月次レポート",
+	})
+	if dependenciesSatisfied(state, []string{"月次レポート"}) {
+		t.Error("planned のままなのに依存が満たされたと判定された")
+	}
+
+	id := taskInstanceID("2026-01-30", "経理", "月次レポート")
+	state.Instances[id].Status = StatusDone
+	if !dependenciesSatisfied(state, []string{"月次レポート"}) {
+		t.Error("done になったのに依存が未達と判定された")
+	}
+
+	// 複数依存のうち1つでも未達なら false
+	registerPlannedInstance(state, OutputRow{
+		Date: d(2026, 1, 31), Project: "経理", TaskName: "経費精算", BaseTaskName: "経費精算",
+	})
+	if dependenciesSatisfied(state, []string{"月次レポート", "経費精算"}) {
+		t.Error("一方が未達なのに依存が満たされたと判定された")
+	}
+}
+
+func TestPendingInstanceInRange(t *testing.T) {
+	state := &State{Instances: make(map[string]*TaskInstance)}
+	registerPlannedInstance(state, OutputRow{
+		Date: d(2026, 1, 30), Project: "経理", TaskName: "月次レポート", BaseTaskName: "月次レポート",
+	})
+
+	pending := pendingInstanceInRange(state, "経理", "月次レポート", "2026-01-01", "2026-01-31")
+	if pending == nil {
+		t.Fatal("範囲内の planned インスタンスが見つからなかった")
+	}
+
+	if got := pendingInstanceInRange(state, "経理", "月次レポート", "2026-02-01", "2026-02-28"); got != nil {
+		t.Errorf("範囲外なのに見つかった: %+v", got)
+	}
+
+	// done になったインスタンスは対象外
+	pending.Status = StatusDone
+	if got := pendingInstanceInRange(state, "経理", "月次レポート", "2026-01-01", "2026-01-31"); got != nil {
+		t.Errorf("done のインスタンスが pending として返った: %+v", got)
+	}
+}
+
+// TestCarryOverAcrossMultipleCycles は、一度 "[繰越]" 接頭辞付きで再生成されたインスタンスが
+// 完了しない限り、その後も月をまたいで繰り返し繰越され続けることを確認する回帰テスト。
+// TaskName（表示名）ではなく BaseTaskName で突き合わせないと、繰越インスタンスは
+// 2回目以降 pendingInstanceInRange から見えなくなり、繰越が1回で止まってしまう。
+func TestCarryOverAcrossMultipleCycles(t *testing.T) {
+	withTestCalendar(t, mapHolidays{}, RollbackBackward)
+
+	tmpl := TaskTemplate{
+		Project:   "経理",
+		TaskName:  "月次レポート",
+		CarryOver: true,
+	}
+	state := &State{Instances: make(map[string]*TaskInstance)}
+
+	// 1月分の未完了インスタンスのみを登録し、以降は一切完了させない
+	registerPlannedInstance(state, newOutputRow(tmpl, d(2026, 1, 20), ""))
+
+	months := []struct {
+		firstDay, prevFirstDay, prevLastDay time.Time
+	}{
+		{d(2026, 2, 1), d(2026, 1, 1), d(2026, 1, 31)},
+		{d(2026, 3, 1), d(2026, 2, 1), d(2026, 2, 28)},
+		{d(2026, 4, 1), d(2026, 3, 1), d(2026, 3, 31)},
+	}
+
+	for i, m := range months {
+		carried := carryOverRow(state, tmpl, m.prevFirstDay, m.prevLastDay, m.firstDay)
+		if carried == nil {
+			t.Fatalf("month %d: 前月の未完了インスタンスが繰越されなかった（繰越が途中で止まった）", i+1)
+		}
+		if carried.TaskName != "[繰越]月次レポート" {
+			t.Errorf("month %d: TaskName = %s, want [繰越]月次レポート", i+1, carried.TaskName)
+		}
+		registerPlannedInstance(state, *carried)
+	}
+}